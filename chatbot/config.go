@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 // Config aggregates runtime configuration loaded from environment variables.
@@ -10,6 +12,26 @@ type Config struct {
 	MaxToken   string
 	MaxAPIBase string
 	QAHost     string
+
+	QAQueueBackend string
+	QASQSURL       string
+	QAAWSRegion    string
+	QADLQSQSURL    string
+	QADLQFile      string
+	QAWorkers      int
+	QAQueueCap     int
+	QAMaxAttempts  int
+	QAJobTimeout   time.Duration
+
+	CallbackSigningSecret string
+
+	HealthAddr           string
+	QAQueueHighWatermark float64
+
+	SessionStore        string
+	SessionRedisAddr    string
+	SessionTTL          time.Duration
+	SessionReapInterval time.Duration
 }
 
 func loadConfig() (Config, error) {
@@ -17,16 +39,84 @@ func loadConfig() (Config, error) {
 		MaxToken:   os.Getenv("MAX_ACCESS_TOKEN"),
 		MaxAPIBase: os.Getenv("MAX_API_BASE"),
 		QAHost:     os.Getenv("QA_HOST"),
+
+		QAQueueBackend: os.Getenv("QA_QUEUE"),
+		QASQSURL:       os.Getenv("QA_SQS_URL"),
+		QAAWSRegion:    os.Getenv("QA_AWS_REGION"),
+		QADLQSQSURL:    os.Getenv("QA_DLQ_SQS_URL"),
+		QADLQFile:      os.Getenv("QA_DLQ_FILE"),
+		QAWorkers:      envInt("QA_WORKERS", 4),
+		QAQueueCap:     envInt("QA_QUEUE_CAPACITY", 100),
+		QAMaxAttempts:  envInt("QA_MAX_ATTEMPTS", 5),
+		QAJobTimeout:   envDuration("QA_JOB_TIMEOUT", 2*time.Minute),
+
+		CallbackSigningSecret: os.Getenv("CALLBACK_SIGNING_SECRET"),
+
+		HealthAddr:           os.Getenv("HEALTH_ADDR"),
+		QAQueueHighWatermark: envFloat("QA_QUEUE_HIGH_WATERMARK", 0.8),
+
+		SessionStore:        os.Getenv("SESSION_STORE"),
+		SessionRedisAddr:    os.Getenv("SESSION_REDIS_ADDR"),
+		SessionTTL:          envDuration("SESSION_TTL", 30*time.Minute),
+		SessionReapInterval: envDuration("SESSION_REAP_INTERVAL", time.Minute),
 	}
 
 	if cfg.MaxToken == "" {
 		return Config{}, fmt.Errorf("MAX_ACCESS_TOKEN is not set")
 	}
+	if cfg.CallbackSigningSecret == "" {
+		return Config{}, fmt.Errorf("CALLBACK_SIGNING_SECRET is not set")
+	}
 	if cfg.MaxAPIBase == "" {
 		cfg.MaxAPIBase = "https://platform-api.max.ru"
 	}
 	if cfg.QAHost == "" {
 		cfg.QAHost = "qa:8080"
 	}
+	if cfg.HealthAddr == "" {
+		cfg.HealthAddr = ":8081"
+	}
 	return cfg, nil
 }
+
+// envInt reads name from the environment, falling back to def if it is
+// unset or not a valid integer.
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// envFloat reads name from the environment, falling back to def if it is
+// unset or not a valid float.
+func envFloat(name string, def float64) float64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// envDuration reads name from the environment, falling back to def if it is
+// unset or not a valid duration.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,81 @@
+package callback
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// nonceCache is a fixed-size, TTL-evicting set used to enforce that each
+// signed token's jti is claimed at most once.
+type nonceCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type nonceEntry struct {
+	jti       string
+	expiresAt time.Time
+}
+
+func newNonceCache(capacity int, ttl time.Duration) *nonceCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &nonceCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// claim records jti as seen and reports whether it was not already claimed
+// (a previous, now-expired claim does not block reuse of the jti). It
+// evicts expired entries, and failing that the oldest, to stay within
+// capacity.
+func (c *nonceCache) claim(jti string, tokenExp time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired()
+
+	if el, ok := c.entries[jti]; ok {
+		if time.Now().Before(el.Value.(*nonceEntry).expiresAt) {
+			return false
+		}
+		c.order.Remove(el)
+		delete(c.entries, jti)
+	}
+
+	retain := c.ttl
+	if untilExp := time.Until(tokenExp); untilExp > retain {
+		retain = untilExp
+	}
+
+	el := c.order.PushBack(&nonceEntry{jti: jti, expiresAt: time.Now().Add(retain)})
+	c.entries[jti] = el
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*nonceEntry).jti)
+	}
+
+	return true
+}
+
+func (c *nonceCache) evictExpired() {
+	now := time.Now()
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if now.After(el.Value.(*nonceEntry).expiresAt) {
+			c.order.Remove(el)
+			delete(c.entries, el.Value.(*nonceEntry).jti)
+		}
+		el = next
+	}
+}
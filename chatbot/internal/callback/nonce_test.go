@@ -0,0 +1,56 @@
+package callback
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNonceCache_RejectsReplayWithinTTL(t *testing.T) {
+	c := newNonceCache(10, time.Minute)
+	exp := time.Now().Add(time.Minute)
+
+	if !c.claim("jti-1", exp) {
+		t.Fatal("first claim of jti-1 should succeed")
+	}
+	if c.claim("jti-1", exp) {
+		t.Fatal("second claim of jti-1 should be rejected as a replay")
+	}
+}
+
+func TestNonceCache_EvictsOldestAtCapacity(t *testing.T) {
+	c := newNonceCache(2, time.Minute)
+	exp := time.Now().Add(time.Minute)
+
+	if !c.claim("jti-1", exp) {
+		t.Fatal("claim of jti-1 should succeed")
+	}
+	if !c.claim("jti-2", exp) {
+		t.Fatal("claim of jti-2 should succeed")
+	}
+	// Pushes the cache past capacity, evicting jti-1.
+	if !c.claim("jti-3", exp) {
+		t.Fatal("claim of jti-3 should succeed")
+	}
+
+	if !c.claim("jti-1", exp) {
+		t.Fatal("jti-1 was evicted for capacity, so it should be claimable again")
+	}
+}
+
+func TestNonceCache_AllowsReuseAfterTTLExpiry(t *testing.T) {
+	c := newNonceCache(10, 10*time.Millisecond)
+	// An already-elapsed token exp means retention falls back to the
+	// cache's own TTL instead of being stretched to cover the token's
+	// (still-valid) lifetime.
+	exp := time.Now()
+
+	if !c.claim("jti-1", exp) {
+		t.Fatal("first claim of jti-1 should succeed")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !c.claim("jti-1", exp) {
+		t.Fatal("claim of jti-1 should succeed again once its entry has expired")
+	}
+}
@@ -0,0 +1,94 @@
+package callback
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	s := NewSigner("test-secret")
+
+	token, err := s.Sign("followup", 42, map[string]string{"foo": "bar"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	action, err := s.Verify(token, 42)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if action.Name != "followup" {
+		t.Errorf("Name = %q, want %q", action.Name, "followup")
+	}
+	if action.UserID != 42 {
+		t.Errorf("UserID = %d, want 42", action.UserID)
+	}
+	if string(action.Arg) != `{"foo":"bar"}` {
+		t.Errorf("Arg = %s, want %s", action.Arg, `{"foo":"bar"}`)
+	}
+}
+
+func TestVerify_WrongUser(t *testing.T) {
+	s := NewSigner("test-secret")
+
+	token, err := s.Sign("followup", 42, nil, time.Minute)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if _, err := s.Verify(token, 43); !errors.Is(err, ErrWrongUser) {
+		t.Fatalf("Verify() err = %v, want ErrWrongUser", err)
+	}
+}
+
+func TestVerify_Expired(t *testing.T) {
+	s := NewSigner("test-secret")
+
+	token, err := s.Sign("followup", 42, nil, -time.Minute)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if _, err := s.Verify(token, 42); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Verify() err = %v, want ErrExpired", err)
+	}
+}
+
+func TestVerify_Replay(t *testing.T) {
+	s := NewSigner("test-secret")
+
+	token, err := s.Sign("followup", 42, nil, time.Minute)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if _, err := s.Verify(token, 42); err != nil {
+		t.Fatalf("first Verify: %v", err)
+	}
+	if _, err := s.Verify(token, 42); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("second Verify() err = %v, want ErrReplayed", err)
+	}
+}
+
+func TestVerify_BadSignature(t *testing.T) {
+	s := NewSigner("test-secret")
+	other := NewSigner("different-secret")
+
+	token, err := other.Sign("followup", 42, nil, time.Minute)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if _, err := s.Verify(token, 42); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("Verify() err = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	s := NewSigner("test-secret")
+
+	if _, err := s.Verify("not-a-token", 42); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("Verify() err = %v, want ErrMalformed", err)
+	}
+}
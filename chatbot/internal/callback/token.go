@@ -0,0 +1,96 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// claims mirrors a JWT claim set, kept deliberately small: who the button
+// was issued to, what it does, an opaque argument, a single-use nonce and
+// the usual timestamps.
+type claims struct {
+	Sub int64           `json:"sub"`
+	Act string          `json:"act"`
+	Arg json.RawMessage `json:"arg,omitempty"`
+	JTI string          `json:"jti"`
+	IAT int64           `json:"iat"`
+	Exp int64           `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var tokenHeader = header{Alg: "HS256", Typ: "VPMXCB"}
+
+// encode produces header.claims.signature, each segment base64url encoded,
+// similar in shape to a JWT.
+func (s *Signer) encode(c claims) (string, error) {
+	headerPart, err := encodeSegment(tokenHeader)
+	if err != nil {
+		return "", err
+	}
+	claimsPart, err := encodeSegment(c)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := headerPart + "." + claimsPart
+	signature := s.sign(signingInput)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// decode verifies the signature over payload and returns its claims.
+func (s *Signer) decode(payload string) (claims, error) {
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return claims{}, ErrMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims{}, ErrMalformed
+	}
+	if !hmac.Equal(signature, s.sign(parts[0]+"."+parts[1])) {
+		return claims{}, ErrBadSignature
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims{}, ErrMalformed
+	}
+
+	var c claims
+	if err := json.Unmarshal(claimsJSON, &c); err != nil {
+		return claims{}, ErrMalformed
+	}
+	return c, nil
+}
+
+func (s *Signer) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// randomJTI returns a random 128-bit nonce, base64url encoded.
+func randomJTI() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
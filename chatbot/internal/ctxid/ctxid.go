@@ -0,0 +1,31 @@
+// Package ctxid attaches a short random correlation ID to a context.Context
+// so a single update's flow can be grepped end-to-end across log lines.
+package ctxid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type key struct{}
+
+// New generates a short random ID and returns a context carrying it.
+func New(ctx context.Context) (context.Context, string) {
+	id := newID()
+	return context.WithValue(ctx, key{}, id), id
+}
+
+// From returns the ID attached to ctx, or "" if none was attached.
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
+
+func newID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
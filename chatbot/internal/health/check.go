@@ -0,0 +1,48 @@
+// Package health implements liveness/readiness checks and the HTTP endpoints
+// that expose them, plus Prometheus metrics scraping, for the bot process.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check is a single readiness probe. It should be cheap enough to run on
+// every /readyz request (use Cached to memoize an expensive one).
+type Check func(ctx context.Context) error
+
+// Noop returns a Check that always succeeds; useful as a default when a
+// dependency hasn't been wired up yet.
+func Noop() Check {
+	return func(context.Context) error { return nil }
+}
+
+// Cached wraps check so it only actually runs once per ttl, returning the
+// last result in between.
+func Cached(ttl time.Duration, check Check) Check {
+	var (
+		mu      sync.Mutex
+		expires time.Time
+		lastErr error
+	)
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if time.Now().Before(expires) {
+			return lastErr
+		}
+
+		lastErr = check(ctx)
+		expires = time.Now().Add(ttl)
+		return lastErr
+	}
+}
+
+// ReadyCheck names a Check for reporting in /readyz.
+type ReadyCheck struct {
+	Name  string
+	Check Check
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the outcome of running a single ReadyCheck.
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Registry collects the ReadyChecks run() registers against and runs them
+// all for each /readyz request.
+type Registry struct {
+	mu     sync.RWMutex
+	checks []ReadyCheck
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a named Check to the registry.
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, ReadyCheck{Name: name, Check: check})
+}
+
+// Run executes every registered Check against ctx and reports whether all of
+// them passed, along with a per-check Result.
+func (r *Registry) Run(ctx context.Context) (bool, []Result) {
+	r.mu.RLock()
+	checks := append([]ReadyCheck(nil), r.checks...)
+	r.mu.RUnlock()
+
+	ok := true
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		res := Result{Name: c.Name, Status: "ok"}
+		if err := c.Check(ctx); err != nil {
+			res.Status = "fail"
+			res.Error = err.Error()
+			ok = false
+		}
+		results = append(results, res)
+	}
+	return ok, results
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server serves /livez, /readyz and /metrics on its own address, separate
+// from the bot's outbound traffic.
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer builds a Server listening on addr. /readyz runs every Check in
+// registry; /metrics serves the default Prometheus registry.
+func NewServer(addr string, registry *Registry) *Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ok, results := registry.Run(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	})
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &Server{httpServer: &http.Server{Addr: addr, Handler: mux}}
+}
+
+// Run starts serving and blocks until ctx is cancelled, then shuts the
+// server down cleanly.
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return s.httpServer.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
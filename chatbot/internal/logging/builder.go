@@ -0,0 +1,46 @@
+// Package logging configures the process-wide zerolog logger and provides a
+// recover-and-log wrapper for the update dispatcher loop.
+package logging
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Builder configures a zerolog.Logger from environment variables: LOG_LEVEL
+// (debug/info/warn/error, default info) and LOG_FORMAT ("json" or
+// "console"; defaults to console, which is meant for local development —
+// deployments should set LOG_FORMAT=json).
+type Builder struct {
+	Level  string
+	Format string
+}
+
+// NewBuilder reads LOG_LEVEL and LOG_FORMAT from the environment.
+func NewBuilder() Builder {
+	return Builder{
+		Level:  os.Getenv("LOG_LEVEL"),
+		Format: os.Getenv("LOG_FORMAT"),
+	}
+}
+
+// Build constructs the logger described by b.
+func (b Builder) Build() zerolog.Logger {
+	level, err := zerolog.ParseLevel(strings.ToLower(b.Level))
+	if err != nil || level == zerolog.NoLevel {
+		level = zerolog.InfoLevel
+	}
+
+	return zerolog.New(b.writer()).Level(level).With().Timestamp().Logger()
+}
+
+func (b Builder) writer() io.Writer {
+	if strings.EqualFold(b.Format, "json") {
+		return os.Stdout
+	}
+	return zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+)
+
+// WithRecover runs fn, recovering from any panic and logging it (with a
+// stack trace) via the logger attached to ctx instead of letting it crash
+// the caller's dispatcher loop.
+func WithRecover(ctx context.Context, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			zerolog.Ctx(ctx).Error().
+				Interface("panic", r).
+				Str("stack", string(debug.Stack())).
+				Msg("recovered from panic")
+		}
+	}()
+	fn()
+}
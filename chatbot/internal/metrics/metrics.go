@@ -0,0 +1,43 @@
+// Package metrics declares the Prometheus collectors the bot exposes on
+// /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// UpdatesReceived counts updates received from the Max Bot API, by type.
+	UpdatesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vopromax_updates_received_total",
+		Help: "Updates received from the Max Bot API, by update type.",
+	}, []string{"update_type"})
+
+	// QARequestDuration is the latency of requests to the QA backend.
+	QARequestDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vopromax_qa_request_duration_seconds",
+		Help:    "Latency of requests to the QA backend.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// QARetriesTotal counts QA backend request retries.
+	QARetriesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "vopromax_qa_retries_total",
+		Help: "QA backend request retries.",
+	})
+
+	// CallbackVerificationFailures counts rejected inline-keyboard callback
+	// payloads, by rejection reason.
+	CallbackVerificationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vopromax_callback_verification_failures_total",
+		Help: "Rejected inline-keyboard callback payloads, by reason.",
+	}, []string{"reason"})
+
+	// QAWorkerPoolSaturation is the fraction of the QA queue's capacity
+	// currently in use (0 when the backend doesn't report a depth).
+	QAWorkerPoolSaturation = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vopromax_qa_worker_pool_saturation",
+		Help: "Fraction of the QA queue's capacity currently in use.",
+	})
+)
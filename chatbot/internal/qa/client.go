@@ -0,0 +1,103 @@
+package qa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/Bolshevichok/vopromax/chatbot/internal/metrics"
+)
+
+// Client talks to the QA backend over HTTP.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client targeting host (cfg.QAHost). A scheme is
+// assumed to be http:// if host does not already carry one.
+func NewClient(host string) *Client {
+	baseURL := host
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type askRequest struct {
+	Question string `json:"question"`
+}
+
+type askResponse struct {
+	Answer string `json:"answer"`
+}
+
+// Ask POSTs question to the QA backend and returns its answer.
+func (c *Client) Ask(ctx context.Context, question string) (string, error) {
+	start := time.Now()
+	answer, err := c.ask(ctx, question)
+	metrics.QARequestDuration.Observe(time.Since(start).Seconds())
+
+	logger := zerolog.Ctx(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Dur("latency", time.Since(start)).Msg("QA backend request failed")
+	} else {
+		logger.Debug().Dur("latency", time.Since(start)).Msg("QA backend request succeeded")
+	}
+	return answer, err
+}
+
+func (c *Client) ask(ctx context.Context, question string) (string, error) {
+	body, err := json.Marshal(askRequest{Question: question})
+	if err != nil {
+		return "", fmt.Errorf("qa: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/ask", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("qa: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("qa: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("qa: unexpected status %d", resp.StatusCode)
+	}
+
+	var out askResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("qa: decode response: %w", err)
+	}
+	return out.Answer, nil
+}
+
+// Ping HEADs the QA backend's base URL to check it is reachable, for use as
+// a health.Check.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+"/", nil)
+	if err != nil {
+		return fmt.Errorf("qa: build ping request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("qa: ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
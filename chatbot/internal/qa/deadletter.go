@@ -0,0 +1,85 @@
+package qa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// deadLetterEntry is the on-disk/on-queue representation of a job that
+// exhausted its retries.
+type deadLetterEntry struct {
+	Job   Job       `json:"job"`
+	Cause string    `json:"cause"`
+	At    time.Time `json:"at"`
+}
+
+// SQSDeadLetter forwards failed jobs to a second SQS queue.
+type SQSDeadLetter struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+// NewSQSDeadLetter creates an SQSDeadLetter that sends to queueURL using
+// client. Pass the same client backing the primary SQSQueue to reuse its
+// credentials and region.
+func NewSQSDeadLetter(client *sqs.Client, queueURL string) *SQSDeadLetter {
+	return &SQSDeadLetter{client: client, queueURL: queueURL}
+}
+
+func (d *SQSDeadLetter) Send(ctx context.Context, job Job, cause error) error {
+	body, err := json.Marshal(deadLetterEntry{Job: job, Cause: cause.Error(), At: time.Now()})
+	if err != nil {
+		return fmt.Errorf("qa: marshal dead letter: %w", err)
+	}
+
+	_, err = d.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(d.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	if err != nil {
+		return fmt.Errorf("qa: send dead letter: %w", err)
+	}
+	return nil
+}
+
+// FileDeadLetter appends failed jobs as newline-delimited JSON to a local
+// file. It is the dead-letter sink of the memory queue backend, and of the
+// sqs backend when no SQS DLQ is configured.
+type FileDeadLetter struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileDeadLetter creates a FileDeadLetter writing to path, creating it if
+// necessary.
+func NewFileDeadLetter(path string) *FileDeadLetter {
+	return &FileDeadLetter{path: path}
+}
+
+func (d *FileDeadLetter) Send(_ context.Context, job Job, cause error) error {
+	line, err := json.Marshal(deadLetterEntry{Job: job, Cause: cause.Error(), At: time.Now()})
+	if err != nil {
+		return fmt.Errorf("qa: marshal dead letter: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("qa: open dead letter file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("qa: write dead letter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package qa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newCorrelationID returns a short random identifier used to track a job
+// across enqueue, retries and the dead-letter sink.
+func newCorrelationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
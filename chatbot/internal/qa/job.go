@@ -0,0 +1,33 @@
+// Package qa implements the asynchronous pipeline between incoming chat
+// questions and the QA backend: a pluggable Queue, an HTTP Client to talk to
+// the backend, and a worker Pool that retries transient failures before
+// giving up to a DeadLetter sink.
+package qa
+
+import "context"
+
+// Job is a single question enqueued for asynchronous processing, together
+// with enough context to deliver the answer back to the right Max chat.
+type Job struct {
+	CorrelationID string `json:"correlation_id"`
+	ChatID        int64  `json:"chat_id"`
+	UserID        int64  `json:"user_id"`
+	Message       string `json:"message"`
+	Attempt       int    `json:"attempt"`
+
+	// ack, if set by the queue backend, finalizes the job's removal from
+	// the source queue once it has been fully handled — either answered
+	// successfully or handed off to the dead-letter sink. It is nil for
+	// backends with no separate acknowledgement step (e.g. MemoryQueue).
+	// Being unexported it is silently skipped by json.Marshal.
+	ack func(context.Context) error
+}
+
+// Ack finalizes the job with its source queue, if the backend that
+// produced it requires one. It is a no-op otherwise.
+func (j Job) Ack(ctx context.Context) error {
+	if j.ack == nil {
+		return nil
+	}
+	return j.ack(ctx)
+}
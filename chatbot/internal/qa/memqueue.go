@@ -0,0 +1,68 @@
+package qa
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryQueue is an in-process, bounded-channel backed Queue. It is the
+// default queue backend and requires no external dependencies.
+//
+// Enqueue and Close coordinate through mu so a send can never race the
+// closing of jobs: Enqueue holds a read lock for the duration of its send
+// (checking closed and sending are one critical section), while Close takes
+// the write lock, which only succeeds once every in-flight Enqueue has
+// released its read lock.
+type MemoryQueue struct {
+	mu     sync.RWMutex
+	closed bool
+	jobs   chan Job
+}
+
+// NewMemoryQueue creates a MemoryQueue with the given channel capacity.
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &MemoryQueue{
+		jobs: make(chan Job, capacity),
+	}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *MemoryQueue) Jobs() <-chan Job {
+	return q.jobs
+}
+
+func (q *MemoryQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	close(q.jobs)
+	return nil
+}
+
+// Depth reports how many jobs are currently buffered and the queue's total
+// capacity, so callers can derive saturation for health checks and metrics.
+func (q *MemoryQueue) Depth() (length, capacity int) {
+	return len(q.jobs), cap(q.jobs)
+}
@@ -0,0 +1,108 @@
+package qa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	maxbot "github.com/max-messenger/max-bot-api-client-go"
+	"github.com/rs/zerolog"
+
+	"github.com/Bolshevichok/vopromax/chatbot/internal/callback"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/session"
+)
+
+// Config carries the QA subsystem's own slice of the bot's environment
+// configuration.
+type Config struct {
+	Host string
+
+	QueueBackend string // "memory" (default) or "sqs"
+	SQSURL       string
+	AWSRegion    string
+
+	DLQSQSURL string
+	DLQFile   string
+
+	Workers     int
+	Capacity    int
+	MaxAttempts int
+	JobTimeout  time.Duration
+
+	Logger zerolog.Logger
+
+	// Sessions and Signer are optional; see PoolConfig.
+	Sessions   session.Store
+	Signer     *callback.Signer
+	SessionTTL time.Duration
+}
+
+// New assembles the QA pipeline (queue, dead-letter sink and worker pool)
+// from cfg and starts the worker pool against ctx.
+func New(ctx context.Context, cfg Config, api *maxbot.Api) (*Pool, error) {
+	queue, deadLetter, err := buildBackend(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewClient(cfg.Host)
+	pool := NewPool(queue, client, api, deadLetter, PoolConfig{
+		Workers:     cfg.Workers,
+		MaxAttempts: cfg.MaxAttempts,
+		JobTimeout:  cfg.JobTimeout,
+		Logger:      cfg.Logger,
+		Sessions:    cfg.Sessions,
+		Signer:      cfg.Signer,
+		SessionTTL:  cfg.SessionTTL,
+	})
+	pool.Start(ctx)
+	return pool, nil
+}
+
+func buildBackend(ctx context.Context, cfg Config) (Queue, DeadLetter, error) {
+	switch cfg.QueueBackend {
+	case "", "memory":
+		return NewMemoryQueue(cfg.Capacity), buildFileDeadLetter(cfg), nil
+	case "sqs":
+		if cfg.SQSURL == "" {
+			return nil, nil, fmt.Errorf("qa: QA_SQS_URL is required when QA_QUEUE=sqs")
+		}
+		queue, err := NewSQSQueue(ctx, cfg.SQSURL, cfg.AWSRegion, cfg.Capacity)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		deadLetter, err := buildSQSDeadLetter(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if deadLetter == nil {
+			deadLetter = buildFileDeadLetter(cfg)
+		}
+		return queue, deadLetter, nil
+	default:
+		return nil, nil, fmt.Errorf("qa: unknown QA_QUEUE backend %q", cfg.QueueBackend)
+	}
+}
+
+func buildFileDeadLetter(cfg Config) DeadLetter {
+	path := cfg.DLQFile
+	if path == "" {
+		path = "qa-dead-letter.jsonl"
+	}
+	return NewFileDeadLetter(path)
+}
+
+func buildSQSDeadLetter(ctx context.Context, cfg Config) (DeadLetter, error) {
+	if cfg.DLQSQSURL == "" {
+		return nil, nil
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, fmt.Errorf("qa: load aws config for dead letter queue: %w", err)
+	}
+	return NewSQSDeadLetter(sqs.NewFromConfig(awsCfg), cfg.DLQSQSURL), nil
+}
@@ -0,0 +1,312 @@
+package qa
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go"
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+	"github.com/rs/zerolog"
+
+	"github.com/Bolshevichok/vopromax/chatbot/internal/callback"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/ctxid"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/metrics"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/session"
+)
+
+// followupTokenTTL bounds how long the "ask a follow-up" button attached to
+// an answer stays valid.
+const followupTokenTTL = 24 * time.Hour
+
+// Stats is a point-in-time snapshot of a Pool's counters.
+type Stats struct {
+	Enqueued  uint64
+	Succeeded uint64
+	Failed    uint64
+	Retried   uint64
+}
+
+// PoolConfig configures a Pool.
+type PoolConfig struct {
+	Workers     int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	Logger      zerolog.Logger
+
+	// JobTimeout bounds how long a single job (all attempts and backoff
+	// combined) may run once dequeued, independent of the process's
+	// shutdown signal. Defaults to defaultJobTimeout.
+	JobTimeout time.Duration
+
+	// Sessions and Signer are optional. When both are set, a successfully
+	// answered question is recorded in the user's session and the reply
+	// carries a signed "ask a follow-up" button.
+	Sessions   session.Store
+	Signer     *callback.Signer
+	SessionTTL time.Duration
+}
+
+// defaultJobTimeout is used when PoolConfig.JobTimeout is unset.
+const defaultJobTimeout = 2 * time.Minute
+
+// Pool drains jobs from a Queue with N workers, asks the QA backend for an
+// answer and replies to the originating Max chat. Jobs that exhaust
+// MaxAttempts are handed to DeadLetter instead of being dropped, applying
+// exponential backoff between attempts.
+type Pool struct {
+	queue      Queue
+	client     *Client
+	api        *maxbot.Api
+	deadLetter DeadLetter
+
+	workers     int
+	maxAttempts int
+	baseBackoff time.Duration
+	jobTimeout  time.Duration
+	logger      zerolog.Logger
+
+	sessions   session.Store
+	signer     *callback.Signer
+	sessionTTL time.Duration
+
+	enqueued  atomic.Uint64
+	succeeded atomic.Uint64
+	failed    atomic.Uint64
+	retried   atomic.Uint64
+
+	wg sync.WaitGroup
+}
+
+// NewPool creates a worker pool over queue.
+func NewPool(queue Queue, client *Client, api *maxbot.Api, deadLetter DeadLetter, cfg PoolConfig) *Pool {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = defaultJobTimeout
+	}
+
+	return &Pool{
+		queue:       queue,
+		client:      client,
+		api:         api,
+		deadLetter:  deadLetter,
+		workers:     cfg.Workers,
+		maxAttempts: cfg.MaxAttempts,
+		baseBackoff: cfg.BaseBackoff,
+		jobTimeout:  cfg.JobTimeout,
+		logger:      cfg.Logger,
+		sessions:    cfg.Sessions,
+		signer:      cfg.Signer,
+		sessionTTL:  cfg.SessionTTL,
+	}
+}
+
+// Enqueue submits a question for asynchronous processing. The job carries
+// the correlation ID already attached to ctx (see ctxid) so the synchronous
+// "message received" log line and the asynchronous QA processing lines can
+// be grepped as a single flow.
+func (p *Pool) Enqueue(ctx context.Context, chatID, userID int64, message string) error {
+	cid := ctxid.From(ctx)
+	if cid == "" {
+		cid = newCorrelationID()
+	}
+	job := Job{
+		CorrelationID: cid,
+		ChatID:        chatID,
+		UserID:        userID,
+		Message:       message,
+	}
+	if err := p.queue.Enqueue(ctx, job); err != nil {
+		return err
+	}
+	p.enqueued.Add(1)
+	return nil
+}
+
+// Start launches the worker goroutines. It returns immediately; call Wait
+// (after Close) to block until in-flight jobs drain. ctx is accepted for
+// symmetry with the rest of the subsystem's constructors but is otherwise
+// unused: job processing is deliberately detached from it (see process), so
+// that jobs already dequeued when ctx is canceled still get a real chance
+// to finish before Wait returns.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+}
+
+// Wait blocks until all workers have returned, i.e. until the queue has been
+// closed and drained.
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+// Close stops accepting new jobs on the underlying queue.
+func (p *Pool) Close() error {
+	return p.queue.Close()
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Enqueued:  p.enqueued.Load(),
+		Succeeded: p.succeeded.Load(),
+		Failed:    p.failed.Load(),
+		Retried:   p.retried.Load(),
+	}
+}
+
+// Ping checks that the QA backend is reachable, for use as a health.Check.
+func (p *Pool) Ping(ctx context.Context) error {
+	return p.client.Ping(ctx)
+}
+
+// Saturation reports the queue's current depth as a fraction of its
+// capacity. ok is false when the underlying Queue backend doesn't report a
+// depth (e.g. SQS).
+func (p *Pool) Saturation() (fraction float64, ok bool) {
+	depther, ok := p.queue.(Depther)
+	if !ok {
+		return 0, false
+	}
+
+	length, capacity := depther.Depth()
+	if capacity == 0 {
+		return 0, false
+	}
+	return float64(length) / float64(capacity), true
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for job := range p.queue.Jobs() {
+		p.process(job)
+	}
+}
+
+// process works a single job through to an answer or a dead-letter hand-off.
+// It deliberately builds its own context from context.Background() rather
+// than accepting one from the caller: the shutdown ctx threaded through
+// Start is canceled the instant SIGTERM arrives, and by the time Close has
+// stopped the queue and Wait is draining in-flight jobs, a canceled ctx
+// would fail every outbound call (and the backoff select) immediately,
+// force-failing every buffered/mid-retry job with zero real attempts. A
+// bounded, independent timeout still keeps a wedged job from blocking
+// shutdown forever.
+func (p *Pool) process(job Job) {
+	logger := p.logger.With().
+		Str("cid", job.CorrelationID).
+		Int64("chat_id", job.ChatID).
+		Int64("user_id", job.UserID).
+		Logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.jobTimeout)
+	defer cancel()
+	ctx = logger.WithContext(ctx)
+
+	var lastErr error
+
+	for attempt := job.Attempt + 1; attempt <= p.maxAttempts; attempt++ {
+		job.Attempt = attempt
+
+		answer, err := p.client.Ask(ctx, job.Message)
+		if err == nil {
+			if sendErr := p.reply(ctx, job, answer); sendErr != nil {
+				logger.Error().Err(sendErr).Msg("failed to deliver QA answer")
+			}
+			p.succeeded.Add(1)
+			p.recordAnswer(ctx, logger, job, answer)
+			if ackErr := job.Ack(ctx); ackErr != nil {
+				logger.Error().Err(ackErr).Msg("failed to ack QA job")
+			}
+			return
+		}
+		lastErr = err
+
+		if attempt == p.maxAttempts {
+			break
+		}
+
+		p.retried.Add(1)
+		metrics.QARetriesTotal.Inc()
+		backoff := p.baseBackoff * time.Duration(uint64(1)<<uint(attempt-1))
+		logger.Info().Int("attempt", attempt).Dur("backoff", backoff).Msg("retrying QA backend request")
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			p.failed.Add(1)
+			p.sendToDeadLetter(ctx, logger, job, ctx.Err())
+			return
+		}
+	}
+
+	p.failed.Add(1)
+	p.sendToDeadLetter(ctx, logger, job, lastErr)
+}
+
+func (p *Pool) reply(ctx context.Context, job Job, answer string) error {
+	msg := maxbot.NewMessage().SetChat(job.ChatID).SetText(answer)
+
+	if p.signer != nil {
+		token, err := p.signer.Sign("followup", job.UserID, nil, followupTokenTTL)
+		if err != nil {
+			p.logger.Error().Err(err).Msg("failed to sign follow-up callback")
+		} else {
+			kb := &maxbot.Keyboard{}
+			kb.AddRow().AddCallback("Ask a follow-up", schemes.DEFAULT, token)
+			msg = msg.AddKeyboard(kb)
+		}
+	}
+
+	_, err := p.api.Messages.Send(ctx, msg)
+	return err
+}
+
+// recordAnswer appends the question/answer pair to the user's session and
+// returns the conversation to idle, ready for either a new question or a
+// follow-up triggered by the button reply attaches.
+func (p *Pool) recordAnswer(ctx context.Context, logger zerolog.Logger, job Job, answer string) {
+	if p.sessions == nil {
+		return
+	}
+
+	state, err := p.sessions.Get(ctx, job.UserID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load session")
+		state = session.NewState()
+	}
+
+	state.Record(job.Message, answer)
+	state.Phase = session.PhaseIdle
+	state.Pending = ""
+
+	if err := p.sessions.Put(ctx, job.UserID, state, p.sessionTTL); err != nil {
+		logger.Error().Err(err).Msg("failed to persist session")
+	}
+}
+
+func (p *Pool) sendToDeadLetter(ctx context.Context, logger zerolog.Logger, job Job, cause error) {
+	if p.deadLetter == nil {
+		return
+	}
+	if err := p.deadLetter.Send(ctx, job, cause); err != nil {
+		logger.Error().Err(err).Msg("failed to dead-letter job")
+		return
+	}
+	// Only ack once the job has safely landed in the dead-letter sink; if
+	// Send failed, leave it unacked so the source queue's visibility
+	// timeout redelivers it instead of losing it outright.
+	if ackErr := job.Ack(ctx); ackErr != nil {
+		logger.Error().Err(ackErr).Msg("failed to ack QA job")
+	}
+}
@@ -0,0 +1,31 @@
+package qa
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrQueueClosed is returned by Enqueue once the queue has been closed.
+var ErrQueueClosed = errors.New("qa: queue is closed")
+
+// Queue fans incoming jobs out to a worker pool. Enqueue must be safe for
+// concurrent use. Jobs returns the channel workers range over; it is closed
+// once the queue has drained after Close.
+type Queue interface {
+	Enqueue(ctx context.Context, job Job) error
+	Jobs() <-chan Job
+	Close() error
+}
+
+// DeadLetter receives jobs that exhausted all retry attempts so a QA outage
+// doesn't silently drop a user's question.
+type DeadLetter interface {
+	Send(ctx context.Context, job Job, cause error) error
+}
+
+// Depther is implemented by Queue backends that can report their current
+// depth and capacity (MemoryQueue; SQS has no equivalent cheap call). It
+// backs worker-pool saturation checks and metrics.
+type Depther interface {
+	Depth() (length, capacity int)
+}
@@ -0,0 +1,115 @@
+package qa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// SQSQueue fronts an AWS SQS queue, selected via QA_QUEUE=sqs. It mirrors
+// the way the fly service splits its outbound traffic across multiple
+// SQS-backed queues (VAAS_SQS_URL/OBSERVATIONS_SQS_URL).
+type SQSQueue struct {
+	client   *sqs.Client
+	queueURL string
+	jobs     chan Job
+	cancel   context.CancelFunc
+}
+
+// NewSQSQueue creates an SQSQueue and starts a background poller that
+// forwards received messages onto the Jobs channel.
+func NewSQSQueue(ctx context.Context, queueURL, region string, capacity int) (*SQSQueue, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("qa: load aws config: %w", err)
+	}
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	q := &SQSQueue{
+		client:   sqs.NewFromConfig(awsCfg),
+		queueURL: queueURL,
+		jobs:     make(chan Job, capacity),
+		cancel:   cancel,
+	}
+	go q.poll(pollCtx)
+	return q, nil
+}
+
+func (q *SQSQueue) Enqueue(ctx context.Context, job Job) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("qa: marshal job: %w", err)
+	}
+
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	if err != nil {
+		return fmt.Errorf("qa: send message: %w", err)
+	}
+	return nil
+}
+
+func (q *SQSQueue) Jobs() <-chan Job {
+	return q.jobs
+}
+
+func (q *SQSQueue) Close() error {
+	q.cancel()
+	return nil
+}
+
+// poll long-polls the SQS queue and forwards decoded jobs onto q.jobs. Each
+// job's Ack deletes the underlying SQS message, but poll itself never
+// deletes: the message stays invisible (via the queue's visibility timeout)
+// until the worker pool calls Ack after a successful answer or a successful
+// dead-letter hand-off, so a crash while the job is buffered or backing off
+// lets SQS redeliver it instead of dropping it.
+func (q *SQSQueue) poll(ctx context.Context) {
+	defer close(q.jobs)
+	for {
+		out, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(q.queueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     20,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, msg := range out.Messages {
+			var job Job
+			if err := json.Unmarshal([]byte(aws.ToString(msg.Body)), &job); err != nil {
+				continue
+			}
+
+			receiptHandle := msg.ReceiptHandle
+			job.ack = func(ctx context.Context) error {
+				_, err := q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+					QueueUrl:      aws.String(q.queueURL),
+					ReceiptHandle: receiptHandle,
+				})
+				return err
+			}
+
+			select {
+			case q.jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
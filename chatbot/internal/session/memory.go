@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process, map-backed Store. It is the default backend
+// and requires no external dependencies. A background reaper evicts expired
+// entries so idle sessions don't accumulate forever.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[int64]memEntry
+}
+
+type memEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a MemoryStore and starts a reaper goroutine that
+// evicts expired entries every reapInterval, until ctx is cancelled.
+func NewMemoryStore(ctx context.Context, reapInterval time.Duration) *MemoryStore {
+	if reapInterval <= 0 {
+		reapInterval = time.Minute
+	}
+
+	s := &MemoryStore{entries: make(map[int64]memEntry)}
+	go s.reap(ctx, reapInterval)
+	return s
+}
+
+func (s *MemoryStore) Get(_ context.Context, userID int64) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return NewState(), nil
+	}
+	return entry.state, nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, userID int64, state State, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[userID] = memEntry{state: state, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, userID)
+	return nil
+}
+
+func (s *MemoryStore) reap(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.evictExpired()
+		}
+	}
+}
+
+func (s *MemoryStore) evictExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for userID, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, userID)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Config carries the session subsystem's own slice of the bot's environment
+// configuration.
+type Config struct {
+	Backend   string // "memory" (default) or "redis"
+	RedisAddr string
+
+	TTL          time.Duration
+	ReapInterval time.Duration
+}
+
+// New assembles the session Store selected by cfg.Backend.
+func New(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", "memory":
+		return NewMemoryStore(ctx, cfg.ReapInterval), nil
+	case "redis":
+		if cfg.RedisAddr == "" {
+			return nil, fmt.Errorf("session: SESSION_REDIS_ADDR is required when SESSION_STORE=redis")
+		}
+		return NewRedisStore(ctx, cfg.RedisAddr)
+	default:
+		return nil, fmt.Errorf("session: unknown SESSION_STORE backend %q", cfg.Backend)
+	}
+}
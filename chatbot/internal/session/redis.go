@@ -0,0 +1,66 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a Redis-backed Store, selected via SESSION_STORE=redis. It
+// relies on Redis key expiry instead of a background reaper.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a RedisStore against addr (SESSION_REDIS_ADDR) and
+// verifies the connection with a PING.
+func NewRedisStore(ctx context.Context, addr string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("session: connect to redis at %s: %w", addr, err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func redisKey(userID int64) string {
+	return fmt.Sprintf("session:%d", userID)
+}
+
+func (s *RedisStore) Get(ctx context.Context, userID int64) (State, error) {
+	raw, err := s.client.Get(ctx, redisKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("session: get from redis: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return State{}, fmt.Errorf("session: decode state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *RedisStore) Put(ctx context.Context, userID int64, state State, ttl time.Duration) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("session: encode state: %w", err)
+	}
+
+	if err := s.client.Set(ctx, redisKey(userID), raw, ttl).Err(); err != nil {
+		return fmt.Errorf("session: put to redis: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, userID int64) error {
+	if err := s.client.Del(ctx, redisKey(userID)).Err(); err != nil {
+		return fmt.Errorf("session: delete from redis: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Package session tracks each user's conversation state across updates, so a
+// follow-up message can be understood in the context of what was asked
+// before, instead of every MessageCreatedUpdate being handled statelessly.
+package session
+
+// Phase names a position in the per-user conversation state machine. A new
+// question moves idle -> asked and its answer moves asked -> idle; pressing
+// the signed follow-up button moves idle -> awaiting_followup, and the next
+// message then moves awaiting_followup -> asked. There is no direct
+// asked -> awaiting_followup or awaiting_followup -> idle transition.
+type Phase string
+
+const (
+	// PhaseIdle is the default state: no question is in flight.
+	PhaseIdle Phase = "idle"
+	// PhaseAsked means a question was just enqueued to the QA backend.
+	PhaseAsked Phase = "asked"
+	// PhaseAwaitingFollowup means the user pressed the signed follow-up
+	// button on an answer, and their next message is sent to the QA backend
+	// as a follow-up to the last recorded question.
+	PhaseAwaitingFollowup Phase = "awaiting_followup"
+)
+
+// maxHistory bounds how many QA pairs a State keeps, so long-lived sessions
+// don't grow without limit.
+const maxHistory = 10
+
+// QAPair is one remembered question/answer exchange.
+type QAPair struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// State is a single user's conversation state.
+type State struct {
+	Phase   Phase             `json:"phase"`
+	History []QAPair          `json:"history"`
+	Pending string            `json:"pending,omitempty"`
+	Scratch map[string]string `json:"scratch,omitempty"`
+}
+
+// NewState returns an idle State with no history.
+func NewState() State {
+	return State{Phase: PhaseIdle}
+}
+
+// Record appends a QA pair to the history, dropping the oldest entry once
+// maxHistory is exceeded.
+func (s *State) Record(question, answer string) {
+	s.History = append(s.History, QAPair{Question: question, Answer: answer})
+	if len(s.History) > maxHistory {
+		s.History = s.History[len(s.History)-maxHistory:]
+	}
+}
+
+// Reset clears the state back to idle, discarding history and scratch data.
+func (s *State) Reset() {
+	*s = NewState()
+}
@@ -0,0 +1,15 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Store persists per-user conversation State. Get on a user with no stored
+// state (new or expired) returns a fresh idle State and a nil error, so
+// callers never need to special-case "no session yet".
+type Store interface {
+	Get(ctx context.Context, userID int64) (State, error)
+	Put(ctx context.Context, userID int64, state State, ttl time.Duration) error
+	Delete(ctx context.Context, userID int64) error
+}
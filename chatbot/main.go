@@ -2,14 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	maxbot "github.com/max-messenger/max-bot-api-client-go"
 	"github.com/max-messenger/max-bot-api-client-go/schemes"
+	"github.com/rs/zerolog"
+
+	"github.com/Bolshevichok/vopromax/chatbot/internal/callback"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/ctxid"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/health"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/logging"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/metrics"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/qa"
+	"github.com/Bolshevichok/vopromax/chatbot/internal/session"
 )
 
 func main() {
@@ -33,8 +45,11 @@ func run() error {
 		return err
 	}
 
+	logger := logging.NewBuilder().Build()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	ctx = logger.WithContext(ctx)
 
 	go func() {
 		signals := make(chan os.Signal, 1)
@@ -43,22 +58,238 @@ func run() error {
 		cancel()
 	}()
 
-	log.Printf("Max bot started. QA host=%s", cfg.QAHost)
+	sessions, err := session.New(ctx, session.Config{
+		Backend:      cfg.SessionStore,
+		RedisAddr:    cfg.SessionRedisAddr,
+		TTL:          cfg.SessionTTL,
+		ReapInterval: cfg.SessionReapInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	signer := callback.NewSigner(cfg.CallbackSigningSecret)
+
+	qaPool, err := qa.New(ctx, qa.Config{
+		Host:         cfg.QAHost,
+		QueueBackend: cfg.QAQueueBackend,
+		SQSURL:       cfg.QASQSURL,
+		AWSRegion:    cfg.QAAWSRegion,
+		DLQSQSURL:    cfg.QADLQSQSURL,
+		DLQFile:      cfg.QADLQFile,
+		Workers:      cfg.QAWorkers,
+		Capacity:     cfg.QAQueueCap,
+		MaxAttempts:  cfg.QAMaxAttempts,
+		JobTimeout:   cfg.QAJobTimeout,
+		Logger:       logger.With().Str("component", "qa").Logger(),
+		Sessions:     sessions,
+		Signer:       signer,
+		SessionTTL:   cfg.SessionTTL,
+	}, api)
+	if err != nil {
+		return err
+	}
+
+	healthErrCh := startHealthServer(ctx, cfg, api, qaPool)
+	go reportQueueSaturation(ctx, qaPool)
+
+	logger.Info().Str("qa_host", cfg.QAHost).Str("health_addr", cfg.HealthAddr).Msg("Max bot started")
 
 	for upd := range api.GetUpdates(ctx) {
-		handleUpdate(api, upd)
+		updCtx, _ := ctxid.New(ctx)
+		logging.WithRecover(updCtx, func() {
+			handleUpdate(updCtx, api, qaPool, sessions, signer, cfg.SessionTTL, upd)
+		})
+	}
+
+	if err := qaPool.Close(); err != nil {
+		logger.Error().Err(err).Msg("failed to close QA queue")
+	}
+	qaPool.Wait()
+
+	if err := <-healthErrCh; err != nil {
+		logger.Error().Err(err).Msg("health server stopped with error")
 	}
 
 	return ctx.Err()
 }
 
-func handleUpdate(api *maxbot.Api, upd interface{}) {
+// startHealthServer wires up the liveness/readiness/metrics endpoints and
+// runs them in the background until ctx is cancelled.
+func startHealthServer(ctx context.Context, cfg Config, api *maxbot.Api, qaPool *qa.Pool) <-chan error {
+	registry := health.NewRegistry()
+	registry.Register("max_api", health.Cached(30*time.Second, func(ctx context.Context) error {
+		_, err := api.Bots.GetBot(ctx)
+		return err
+	}))
+	registry.Register("qa_backend", qaPool.Ping)
+	registry.Register("qa_queue_depth", func(context.Context) error {
+		fraction, ok := qaPool.Saturation()
+		if !ok || fraction < cfg.QAQueueHighWatermark {
+			return nil
+		}
+		return fmt.Errorf("qa queue saturation %.2f is at or above the %.2f watermark", fraction, cfg.QAQueueHighWatermark)
+	})
+
+	server := health.NewServer(cfg.HealthAddr, registry)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx)
+	}()
+	return errCh
+}
+
+// reportQueueSaturation periodically publishes the QA queue's saturation as
+// a gauge until ctx is cancelled.
+func reportQueueSaturation(ctx context.Context, qaPool *qa.Pool) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if fraction, ok := qaPool.Saturation(); ok {
+				metrics.QAWorkerPoolSaturation.Set(fraction)
+			}
+		}
+	}
+}
+
+// resetCommand is the text command that clears a user's session.
+const resetCommand = "/reset"
+
+func handleUpdate(ctx context.Context, api *maxbot.Api, qaPool *qa.Pool, sessions session.Store, signer *callback.Signer, sessionTTL time.Duration, upd schemes.UpdateInterface) {
+	logger := zerolog.Ctx(ctx).With().
+		Str("update_type", string(upd.GetUpdateType())).
+		Int64("chat_id", upd.GetChatID()).
+		Int64("user_id", upd.GetUserID()).
+		Str("cid", ctxid.From(ctx)).
+		Logger()
+	ctx = logger.WithContext(ctx)
+
+	metrics.UpdatesReceived.WithLabelValues(string(upd.GetUpdateType())).Inc()
+
 	switch u := upd.(type) {
 	case *schemes.MessageCreatedUpdate:
-		log.Printf("message from %d: %s", u.Message.Sender.UserId, u.GetText())
+		handleMessage(ctx, logger, api, qaPool, sessions, sessionTTL, u)
 	case *schemes.MessageCallbackUpdate:
-		log.Printf("callback from %d payload=%s", u.Callback.User.UserId, u.Callback.Payload)
+		handleCallback(ctx, logger, api, sessions, signer, sessionTTL, u)
+	default:
+		logger.Info().Msg("unhandled update")
+	}
+}
+
+// handleMessage drives the idle -> asked transition: a plain question is
+// enqueued as-is, while text arriving in PhaseAwaitingFollowup is enqueued
+// together with the question it follows up on.
+func handleMessage(ctx context.Context, logger zerolog.Logger, api *maxbot.Api, qaPool *qa.Pool, sessions session.Store, sessionTTL time.Duration, u *schemes.MessageCreatedUpdate) {
+	text := u.GetText()
+	logger.Info().Str("text", text).Msg("message received")
+
+	if text == resetCommand {
+		if err := sessions.Delete(ctx, u.GetUserID()); err != nil {
+			logger.Error().Err(err).Msg("failed to reset session")
+		}
+		sendText(ctx, api, u.GetChatID(), "Conversation reset.")
+		return
+	}
+
+	state, err := sessions.Get(ctx, u.GetUserID())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load session")
+		state = session.NewState()
+	}
+
+	question := text
+	if state.Phase == session.PhaseAwaitingFollowup && len(state.History) > 0 {
+		last := state.History[len(state.History)-1]
+		question = fmt.Sprintf("Follow-up to %q: %s", last.Question, text)
+	}
+
+	state.Phase = session.PhaseAsked
+	state.Pending = text
+	if err := sessions.Put(ctx, u.GetUserID(), state, sessionTTL); err != nil {
+		logger.Error().Err(err).Msg("failed to persist session")
+	}
+
+	if err := qaPool.Enqueue(ctx, u.GetChatID(), u.GetUserID(), question); err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue question")
+	}
+}
+
+// handleCallback verifies a signed inline-keyboard callback and, for the
+// "followup" action, moves the user's session into PhaseAwaitingFollowup so
+// their next message is treated as a follow-up question.
+func handleCallback(ctx context.Context, logger zerolog.Logger, api *maxbot.Api, sessions session.Store, signer *callback.Signer, sessionTTL time.Duration, u *schemes.MessageCallbackUpdate) {
+	action, err := signer.Verify(u.Callback.Payload, u.Callback.User.UserId)
+	if err != nil {
+		logger.Warn().Err(err).Msg("rejected callback")
+		metrics.CallbackVerificationFailures.WithLabelValues(callbackFailureReason(err)).Inc()
+		answerInvalidCallback(ctx, api, u.Callback.CallbackID)
+		return
+	}
+	logger.Info().Str("action", action.Name).RawJSON("arg", action.Arg).Msg("callback verified")
+
+	switch action.Name {
+	case "followup":
+		state, err := sessions.Get(ctx, action.UserID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load session")
+			state = session.NewState()
+		}
+		state.Phase = session.PhaseAwaitingFollowup
+		if err := sessions.Put(ctx, action.UserID, state, sessionTTL); err != nil {
+			logger.Error().Err(err).Msg("failed to persist session")
+		}
+		answerCallback(ctx, api, u.Callback.CallbackID, "Go ahead, ask your follow-up question.")
+	default:
+		logger.Warn().Str("action", action.Name).Msg("unknown callback action")
+	}
+}
+
+// callbackFailureReason maps a callback.Verify error to a short label
+// suitable for a metrics dimension.
+func callbackFailureReason(err error) string {
+	switch {
+	case errors.Is(err, callback.ErrExpired):
+		return "expired"
+	case errors.Is(err, callback.ErrWrongUser):
+		return "wrong_user"
+	case errors.Is(err, callback.ErrReplayed):
+		return "replayed"
+	case errors.Is(err, callback.ErrBadSignature):
+		return "bad_signature"
+	case errors.Is(err, callback.ErrMalformed):
+		return "malformed"
 	default:
-		log.Printf("update: %#v", u)
+		return "unknown"
+	}
+}
+
+// answerInvalidCallback tells the user their button press could not be
+// processed instead of letting the client spin waiting for a response.
+func answerInvalidCallback(ctx context.Context, api *maxbot.Api, callbackID string) {
+	answerCallback(ctx, api, callbackID, "This button is no longer valid, please try again.")
+}
+
+// answerCallback sends notification as the toast shown in response to a
+// pressed inline-keyboard button.
+func answerCallback(ctx context.Context, api *maxbot.Api, callbackID, notification string) {
+	_, err := api.Messages.AnswerOnCallback(ctx, callbackID, &schemes.CallbackAnswer{
+		Notification: notification,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("callback_id", callbackID).Msg("failed to answer callback")
+	}
+}
+
+// sendText sends a plain text message to chatID, for bot-originated replies
+// that fall outside the QA pipeline (e.g. /reset).
+func sendText(ctx context.Context, api *maxbot.Api, chatID int64, text string) {
+	msg := maxbot.NewMessage().SetChat(chatID).SetText(text)
+	if _, err := api.Messages.Send(ctx, msg); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int64("chat_id", chatID).Msg("failed to send message")
 	}
 }